@@ -0,0 +1,77 @@
+package main
+
+import (
+    "crypto/subtle"
+    "fmt"
+    "net"
+    "strings"
+    "sync"
+    "time"
+)
+
+const authFailureLogInterval = time.Minute
+
+// authGate enforces the pre-shared-key check and index allow-list shared by
+// the TCP and HTTP front-ends.
+type authGate struct {
+    psk            string
+    allowedIndexes map[string]bool
+
+    mu           sync.Mutex
+    lastLoggedAt map[string]time.Time
+}
+
+func newAuthGate(cfg Config) *authGate {
+    allowed := make(map[string]bool, len(cfg.AllowedIndexes))
+    for _, index := range cfg.AllowedIndexes {
+        allowed[index] = true
+    }
+    return &authGate{
+        psk:            cfg.PSK,
+        allowedIndexes: allowed,
+        lastLoggedAt:   make(map[string]time.Time),
+    }
+}
+
+// checkPSK reports whether provided matches the configured pre-shared key.
+// An empty configured key disables the check entirely.
+func (g *authGate) checkPSK(provided string) bool {
+    if g.psk == "" {
+        return true
+    }
+    return subtle.ConstantTimeCompare([]byte(provided), []byte(g.psk)) == 1
+}
+
+// checkAuthHeader is the HTTP equivalent of checkPSK, reading the
+// "Authorization: Bearer <psk>" header used by the HTTP API.
+func (g *authGate) checkAuthHeader(header string) bool {
+    return g.checkPSK(strings.TrimPrefix(header, "Bearer "))
+}
+
+// checkIndex reports whether index is allowed to be reindexed. An empty
+// allow-list permits any index, preserving the previous behaviour.
+func (g *authGate) checkIndex(index string) bool {
+    if len(g.allowedIndexes) == 0 {
+        return true
+    }
+    return g.allowedIndexes[index]
+}
+
+// logUnauthorized logs a failed auth attempt for clientAddr, at most once
+// per authFailureLogInterval per client host (the port is ignored), so a
+// misbehaving or hostile client can't flood the log by reconnecting.
+func (g *authGate) logUnauthorized(clientAddr string) {
+    key := clientAddr
+    if host, _, err := net.SplitHostPort(clientAddr); err == nil {
+        key = host
+    }
+
+    g.mu.Lock()
+    defer g.mu.Unlock()
+
+    if last, ok := g.lastLoggedAt[key]; ok && time.Since(last) < authFailureLogInterval {
+        return
+    }
+    g.lastLoggedAt[key] = time.Now()
+    fmt.Printf("Unauthorized request from %s\n", clientAddr)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+    "encoding/json"
+    "os"
+)
+
+const (
+    configFilePath = "/etc/sphinxsearch/reindexer.json"
+    defaultLogPath = "/var/log/sphinxindexer.log"
+)
+
+type HTTPConfig struct {
+    Enabled bool
+    Host    string
+    Port    int
+}
+
+type TLSConfig struct {
+    CertFile string
+    KeyFile  string
+}
+
+// ScheduleEntry declares a background reindex job. Set exactly one of Every
+// (a time.ParseDuration string, e.g. "15m") or Cron (a standard five-field
+// cron expression, e.g. "0 3 * * *"). There is no Full field: full vs.
+// incremental reindexing is chosen by Index alone (e.g. "products" vs.
+// "products_full"), matching separate indexer config sections - there's no
+// separate CLI flag for it. loadConfig rejects unrecognized schedule keys
+// (like a leftover "full") instead of silently ignoring them.
+type ScheduleEntry struct {
+    Index string
+    Every string
+    Cron  string
+}
+
+// LoggingConfig configures the structured JSON log and its rotating file
+// sink. MaxSizeMB/MaxBackups/MaxAgeDays follow lumberjack's conventions:
+// zero means "no limit" for backups and age, and lumberjack defaults
+// MaxSizeMB to 100 if left at zero.
+type LoggingConfig struct {
+    Path       string
+    MaxSizeMB  int
+    MaxBackups int
+    MaxAgeDays int
+    Compress   bool
+    Stdout     bool
+}
+
+type Config struct {
+    HTTP                   HTTPConfig
+    TLS                    TLSConfig
+    PSK                    string
+    AllowedIndexes         []string
+    RotationTimeoutSeconds int
+    Schedule               []ScheduleEntry
+    MaxConcurrentReindexes int
+    Logging                LoggingConfig
+}
+
+func defaultConfig() Config {
+    return Config{
+        HTTP: HTTPConfig{
+            Enabled: false,
+            Host:    serverHost,
+            Port:    5019,
+        },
+        MaxConcurrentReindexes: 1,
+        Logging: LoggingConfig{
+            Path:   defaultLogPath,
+            Stdout: true,
+        },
+    }
+}
+
+func loadConfig(path string) (Config, error) {
+    cfg := defaultConfig()
+
+    file, err := os.Open(path)
+    if err != nil {
+        if os.IsNotExist(err) {
+            return cfg, nil
+        }
+        return cfg, err
+    }
+    defer file.Close()
+
+    decoder := json.NewDecoder(file)
+    decoder.DisallowUnknownFields()
+    if err := decoder.Decode(&cfg); err != nil {
+        return cfg, err
+    }
+
+    return cfg, nil
+}
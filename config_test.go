@@ -0,0 +1,44 @@
+package main
+
+import (
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+func writeConfigFile(t *testing.T, contents string) string {
+    t.Helper()
+    path := filepath.Join(t.TempDir(), "reindexer.json")
+    if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+        t.Fatalf("WriteFile: %v", err)
+    }
+    return path
+}
+
+func TestLoadConfigRejectsUnknownScheduleField(t *testing.T) {
+    path := writeConfigFile(t, `{"Schedule":[{"Index":"products","Every":"15m","full":true}]}`)
+    if _, err := loadConfig(path); err == nil {
+        t.Fatal("expected an error for the unrecognized \"full\" schedule key, got nil")
+    }
+}
+
+func TestLoadConfigAcceptsValidSchedule(t *testing.T) {
+    path := writeConfigFile(t, `{"Schedule":[{"Index":"products","Every":"15m"}]}`)
+    cfg, err := loadConfig(path)
+    if err != nil {
+        t.Fatalf("loadConfig: %v", err)
+    }
+    if len(cfg.Schedule) != 1 || cfg.Schedule[0].Index != "products" {
+        t.Fatalf("got Schedule %+v, want a single products entry", cfg.Schedule)
+    }
+}
+
+func TestLoadConfigMissingFileUsesDefaults(t *testing.T) {
+    cfg, err := loadConfig(filepath.Join(t.TempDir(), "does-not-exist.json"))
+    if err != nil {
+        t.Fatalf("loadConfig: %v", err)
+    }
+    if cfg.MaxConcurrentReindexes != defaultConfig().MaxConcurrentReindexes {
+        t.Fatalf("got MaxConcurrentReindexes %d, want the default", cfg.MaxConcurrentReindexes)
+    }
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "log/slog"
+    "net"
+    "net/http"
+    "strconv"
+    "time"
+
+    "github.com/skurik/sphinx-reindexer/reindexer"
+    "github.com/skurik/sphinx-reindexer/scheduler"
+)
+
+type reindexRequest struct {
+    Index string `json:"index"`
+}
+
+type statusResponse struct {
+    Running    string                         `json:"running,omitempty"`
+    LastRunAt  string                         `json:"last_run_at,omitempty"`
+    LastError  string                         `json:"last_error,omitempty"`
+    QueueDepth int                            `json:"queue_depth"`
+    Indexes    map[string]indexStatusResponse `json:"indexes,omitempty"`
+}
+
+type indexStatusResponse struct {
+    NextFireAt    string `json:"next_fire_at,omitempty"`
+    LastSuccessAt string `json:"last_success_at,omitempty"`
+    LastFailureAt string `json:"last_failure_at,omitempty"`
+    LastError     string `json:"last_error,omitempty"`
+}
+
+func startHTTPServer(host string, port int, svc *reindexer.Service, sched *scheduler.Scheduler, gate *authGate, tlsCfg TLSConfig, logger *slog.Logger) {
+    mux := http.NewServeMux()
+    mux.HandleFunc("/ping", handlePing)
+    mux.HandleFunc("/reindex", handleHTTPReindex(sched, gate, logger))
+    mux.HandleFunc("/status", handleStatus(svc, sched))
+
+    addr := net.JoinHostPort(host, strconv.Itoa(port))
+    fmt.Printf("Listening for HTTP on %s\n", addr)
+
+    var err error
+    if tlsCfg.CertFile != "" && tlsCfg.KeyFile != "" {
+        err = http.ListenAndServeTLS(addr, tlsCfg.CertFile, tlsCfg.KeyFile, mux)
+    } else {
+        err = http.ListenAndServe(addr, mux)
+    }
+    if err != nil {
+        fmt.Println("Error serving HTTP:", err.Error())
+    }
+}
+
+func handlePing(w http.ResponseWriter, r *http.Request) {
+    writeJSON(w, Response{Message: "pong"})
+}
+
+func handleHTTPReindex(sched *scheduler.Scheduler, gate *authGate, logger *slog.Logger) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != http.MethodPost {
+            http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+            return
+        }
+
+        logger.Info("request_received", "client_addr", r.RemoteAddr)
+
+        if !gate.checkAuthHeader(r.Header.Get("Authorization")) {
+            gate.logUnauthorized(r.RemoteAddr)
+            writeJSON(w, Response{Error: "unauthorized"})
+            return
+        }
+
+        var req reindexRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            writeJSON(w, Response{Error: "Could not decode the request JSON: " + err.Error()})
+            return
+        }
+
+        if !gate.checkIndex(req.Index) {
+            writeJSON(w, Response{Error: "index not allowed"})
+            return
+        }
+
+        if err := sched.Trigger(req.Index); err != nil {
+            writeJSON(w, Response{Error: "Reindexing error: " + err.Error()})
+            return
+        }
+
+        writeJSON(w, Response{Message: "OK"})
+    }
+}
+
+func handleStatus(svc *reindexer.Service, sched *scheduler.Scheduler) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        st := svc.Status()
+        resp := statusResponse{Running: st.Running, LastError: st.LastError}
+        if !st.LastRunAt.IsZero() {
+            resp.LastRunAt = st.LastRunAt.Format(time.RFC3339)
+        }
+
+        schedSt := sched.Status()
+        resp.QueueDepth = schedSt.QueueDepth
+        if len(schedSt.Indexes) > 0 {
+            resp.Indexes = make(map[string]indexStatusResponse, len(schedSt.Indexes))
+            for index, idxSt := range schedSt.Indexes {
+                var idxResp indexStatusResponse
+                if !idxSt.NextFireAt.IsZero() {
+                    idxResp.NextFireAt = idxSt.NextFireAt.Format(time.RFC3339)
+                }
+                if !idxSt.LastSuccessAt.IsZero() {
+                    idxResp.LastSuccessAt = idxSt.LastSuccessAt.Format(time.RFC3339)
+                }
+                if !idxSt.LastFailureAt.IsZero() {
+                    idxResp.LastFailureAt = idxSt.LastFailureAt.Format(time.RFC3339)
+                }
+                idxResp.LastError = idxSt.LastError
+                resp.Indexes[index] = idxResp
+            }
+        }
+
+        writeJSON(w, resp)
+    }
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(v)
+}
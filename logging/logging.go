@@ -0,0 +1,57 @@
+// Package logging builds the structured, size/age-rotated logger shared by
+// the TCP front-end, the HTTP front-end and the reindexer core.
+package logging
+
+import (
+    "io"
+    "log/slog"
+    "os"
+
+    "gopkg.in/natefinch/lumberjack.v2"
+)
+
+// Config configures the log sink. Path empty disables file logging
+// entirely, in which case Stdout is forced on so the process still logs
+// somewhere.
+type Config struct {
+    Path       string
+    MaxSizeMB  int
+    MaxBackups int
+    MaxAgeDays int
+    Compress   bool
+    Stdout     bool
+}
+
+// New builds a JSON slog.Logger with "ts", "level" and "event" fields,
+// backed by Config. Callers log with the event name as the message, e.g.
+// logger.Info("reindex_started", "index", indexName).
+func New(cfg Config) *slog.Logger {
+    var writers []io.Writer
+    if cfg.Path != "" {
+        writers = append(writers, &lumberjack.Logger{
+            Filename:   cfg.Path,
+            MaxSize:    cfg.MaxSizeMB,
+            MaxBackups: cfg.MaxBackups,
+            MaxAge:     cfg.MaxAgeDays,
+            Compress:   cfg.Compress,
+        })
+    }
+    if cfg.Stdout || len(writers) == 0 {
+        writers = append(writers, os.Stdout)
+    }
+
+    handler := slog.NewJSONHandler(io.MultiWriter(writers...), &slog.HandlerOptions{
+        ReplaceAttr: renameStandardKeys,
+    })
+    return slog.New(handler)
+}
+
+func renameStandardKeys(groups []string, a slog.Attr) slog.Attr {
+    switch a.Key {
+    case slog.TimeKey:
+        a.Key = "ts"
+    case slog.MessageKey:
+        a.Key = "event"
+    }
+    return a
+}
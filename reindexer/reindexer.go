@@ -0,0 +1,168 @@
+package reindexer
+
+import (
+    "bufio"
+    "errors"
+    "io"
+    "log/slog"
+    "math"
+    "os"
+    "os/exec"
+    "regexp"
+    "strconv"
+    "sync"
+    "time"
+)
+
+const (
+    dateFormat      = "Mon Jan 2 15:04:05 2006"
+    indexerBinPath  = "/usr/bin/indexer"
+    configPath      = "/etc/sphinxsearch/sphinx.conf"
+    searchdLogPath  = "/var/log/sphinxsearch/searchd.log"
+    rotationMarker  = "rotating index: all indexes done"
+)
+
+var (
+    searchdLogLinePrefix = regexp.MustCompile(`\[([^\]]*)\.([0-9]{3})\s([0-9]{4})\]`)
+    baseDate, _          = time.Parse(dateFormat, "Fri Sep 7 10:00:00 2012")
+)
+
+// Status is a snapshot of a Service's current and most recent activity.
+type Status struct {
+    Running   string
+    LastRunAt time.Time
+    LastError string
+}
+
+// Service runs `indexer` invocations and keeps track of their outcome so it
+// can be shared between the TCP and HTTP front-ends.
+type Service struct {
+    rotationTimeout time.Duration
+    logger          *slog.Logger
+
+    mu        sync.Mutex
+    running   string
+    lastRunAt time.Time
+    lastErr   error
+}
+
+// NewService creates a Service. rotationTimeout bounds how long Reindex
+// waits for searchd to report a completed rotation; a zero value falls
+// back to defaultRotationTimeout. A nil logger discards all log output.
+func NewService(rotationTimeout time.Duration, logger *slog.Logger) *Service {
+    if rotationTimeout <= 0 {
+        rotationTimeout = defaultRotationTimeout
+    }
+    if logger == nil {
+        logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+    }
+    return &Service{rotationTimeout: rotationTimeout, logger: logger}
+}
+
+// Status returns the current and most recently completed reindex state.
+func (s *Service) Status() Status {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    st := Status{Running: s.running, LastRunAt: s.lastRunAt}
+    if s.lastErr != nil {
+        st.LastError = s.lastErr.Error()
+    }
+    return st
+}
+
+// Reindex runs `indexer --rotate` for indexName and waits for searchd to
+// report that the rotation completed.
+func (s *Service) Reindex(indexName string) error {
+    s.mu.Lock()
+    s.running = indexName
+    s.mu.Unlock()
+
+    s.logger.Info("reindex_started", "index", indexName)
+    start := time.Now()
+
+    err := s.doReindex(indexName)
+
+    duration := time.Since(start)
+    if err != nil {
+        s.logger.Error("error", "index", indexName, "duration_ms", duration.Milliseconds(), "err", err.Error())
+    } else {
+        s.logger.Info("reindex_completed", "index", indexName, "duration_ms", duration.Milliseconds())
+    }
+
+    s.mu.Lock()
+    s.running = ""
+    s.lastRunAt = time.Now()
+    s.lastErr = err
+    s.mu.Unlock()
+
+    return err
+}
+
+func (s *Service) doReindex(indexName string) error {
+    lastDate, err := getLastTimestamp(searchdLogPath)
+    if err != nil {
+        return err
+    }
+    cmd := exec.Command(indexerBinPath, "--config", configPath, "--rotate", "--quiet", indexName)
+    if err := cmd.Run(); err != nil {
+        return err
+    }
+
+    if err := waitForRotation(searchdLogPath, rotationMarker, lastDate, s.rotationTimeout); err != nil {
+        return err
+    }
+
+    s.logger.Info("rotation_detected", "index", indexName)
+    return nil
+}
+
+func readLines(file *os.File) ([]string, error) {
+    var lines []string
+    scanner := bufio.NewScanner(file)
+    for scanner.Scan() {
+        lines = append(lines, scanner.Text())
+    }
+    return lines, scanner.Err()
+}
+
+func getLastTimestamp(filePath string) (time.Time, error) {
+    file, err := os.Open(filePath)
+    if err != nil {
+        return time.Time{}, err
+    }
+    defer file.Close()
+    fileInfo, err := file.Stat()
+    if err != nil {
+        return time.Time{}, err
+    }
+    fileLength := fileInfo.Size()
+    file.Seek(int64(-math.Min(1024, math.Max(float64(fileLength-1), 0))), 2)
+    lines, err := readLines(file)
+    if err != nil {
+        return time.Time{}, err
+    }
+
+    if len(lines) == 0 {
+        return baseDate, nil
+    }
+
+    return timeFromLog(lines[len(lines)-1])
+}
+
+func timeFromLog(line string) (time.Time, error) {
+    match := searchdLogLinePrefix.FindStringSubmatch(line)
+    if len(match) > 1 {
+        dateStr := match[1]
+        milliseconds := match[2]
+        year := match[3]
+        date, _ := time.Parse(dateFormat, dateStr+" "+year)
+        return date.Add(time.Millisecond * time.Duration(atoi(milliseconds))), nil
+    }
+
+    return time.Time{}, errors.New("Could not match a timestamp prefix")
+}
+
+func atoi(value string) (ret int) {
+    ret, _ = strconv.Atoi(value)
+    return
+}
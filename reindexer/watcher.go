@@ -0,0 +1,191 @@
+package reindexer
+
+import (
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "regexp"
+    "strings"
+    "time"
+
+    "github.com/fsnotify/fsnotify"
+)
+
+const (
+    defaultRotationTimeout = 60 * time.Second
+    pollInterval           = 200 * time.Millisecond
+)
+
+// waitForRotation blocks until a line matching expression with a timestamp
+// after threshold is appended to filePath, the timeout elapses, or an error
+// occurs. The file is opened at its current end, so lines already in it when
+// waitForRotation is called are not examined. It prefers an fsnotify watch on the file's directory and falls
+// back to polling on platforms without inotify support.
+func waitForRotation(filePath, expression string, threshold time.Time, timeout time.Duration) error {
+    if timeout <= 0 {
+        timeout = defaultRotationTimeout
+    }
+    re := regexp.MustCompile(`\[([^\]]*)\.([0-9]{3})\s([0-9]{4})\].*` + expression)
+
+    watcher, err := fsnotify.NewWatcher()
+    if err != nil {
+        return pollForRotation(filePath, re, threshold, timeout)
+    }
+    defer watcher.Close()
+
+    if err := watcher.Add(filepath.Dir(filePath)); err != nil {
+        return pollForRotation(filePath, re, threshold, timeout)
+    }
+
+    file, offset, err := openAtEnd(filePath)
+    if err != nil {
+        return err
+    }
+    defer func() { file.Close() }()
+
+    deadline := time.After(timeout)
+    for {
+        select {
+        case event, ok := <-watcher.Events:
+            if !ok {
+                return fmt.Errorf("rotation watcher for %s closed unexpectedly", filePath)
+            }
+            if filepath.Clean(event.Name) != filepath.Clean(filePath) {
+                continue
+            }
+
+            if event.Op&(fsnotify.Create|fsnotify.Rename) != 0 {
+                file.Close()
+                newFile, newOffset, err := openAtEnd(filePath)
+                if err != nil {
+                    return err
+                }
+                file, offset = newFile, newOffset
+                continue
+            }
+
+            if event.Op&fsnotify.Write == 0 {
+                continue
+            }
+
+            matched, newOffset, err := scanNewLines(file, offset, re, threshold)
+            if err != nil {
+                return err
+            }
+            offset = newOffset
+            if matched {
+                return nil
+            }
+        case err, ok := <-watcher.Errors:
+            if !ok {
+                return fmt.Errorf("rotation watcher for %s closed unexpectedly", filePath)
+            }
+            return err
+        case <-deadline:
+            return fmt.Errorf("timed out after %s waiting for rotation of %s", timeout, filePath)
+        }
+    }
+}
+
+// pollForRotation is the fsnotify fallback: it re-reads the tail of the log
+// on a fixed interval and detects rotation by inode change.
+func pollForRotation(filePath string, re *regexp.Regexp, threshold time.Time, timeout time.Duration) error {
+    file, offset, err := openAtEnd(filePath)
+    if err != nil {
+        return err
+    }
+    defer file.Close()
+
+    deadline := time.Now().Add(timeout)
+    ticker := time.NewTicker(pollInterval)
+    defer ticker.Stop()
+
+    for {
+        if time.Now().After(deadline) {
+            return fmt.Errorf("timed out after %s waiting for rotation of %s", timeout, filePath)
+        }
+
+        if rotated, err := fileWasRotated(file, filePath); err == nil && rotated {
+            file.Close()
+            file, offset, err = openAtEnd(filePath)
+            if err != nil {
+                return err
+            }
+        }
+
+        matched, newOffset, err := scanNewLines(file, offset, re, threshold)
+        if err != nil {
+            return err
+        }
+        offset = newOffset
+        if matched {
+            return nil
+        }
+
+        <-ticker.C
+    }
+}
+
+func fileWasRotated(file *os.File, filePath string) (bool, error) {
+    current, err := file.Stat()
+    if err != nil {
+        return false, err
+    }
+    onDisk, err := os.Stat(filePath)
+    if err != nil {
+        return false, err
+    }
+    return !os.SameFile(current, onDisk), nil
+}
+
+func openAtEnd(filePath string) (*os.File, int64, error) {
+    file, err := os.Open(filePath)
+    if err != nil {
+        return nil, 0, err
+    }
+    info, err := file.Stat()
+    if err != nil {
+        file.Close()
+        return nil, 0, err
+    }
+    return file, info.Size(), nil
+}
+
+// scanNewLines reads the complete lines appended to file since offset,
+// checking each against re for a timestamp after threshold. It returns the
+// offset of the first byte of any trailing, not-yet-terminated line so the
+// caller can resume from there.
+func scanNewLines(file *os.File, offset int64, re *regexp.Regexp, threshold time.Time) (matched bool, newOffset int64, err error) {
+    if _, err = file.Seek(offset, io.SeekStart); err != nil {
+        return false, offset, err
+    }
+    data, err := io.ReadAll(file)
+    if err != nil {
+        return false, offset, err
+    }
+
+    // Split always yields one trailing element that is not a complete line:
+    // "" when data ends in "\n", or the not-yet-terminated tail otherwise.
+    // Drop it either way so consumed never counts past the last "\n" we saw.
+    lines := strings.Split(string(data), "\n")
+    complete := lines[:len(lines)-1]
+
+    consumed := 0
+    for _, line := range complete {
+        consumed += len(line) + 1
+        match := re.FindStringSubmatch(line)
+        if len(match) <= 1 {
+            continue
+        }
+        date, err := timeFromLog(line)
+        if err != nil {
+            return false, offset + int64(consumed), err
+        }
+        if date.After(threshold) {
+            matched = true
+        }
+    }
+
+    return matched, offset + int64(consumed), nil
+}
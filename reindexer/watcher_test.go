@@ -0,0 +1,55 @@
+package reindexer
+
+import (
+    "os"
+    "regexp"
+    "testing"
+    "time"
+)
+
+// TestScanNewLinesAcrossTwoWrites guards against a bug where a chunk ending
+// in "\n" left the trailing empty split element in the line count, causing
+// consumed to overshoot by one byte and the next write's leading byte to be
+// skipped - dropping the "[" off a timestamp and breaking the match.
+func TestScanNewLinesAcrossTwoWrites(t *testing.T) {
+    re := regexp.MustCompile(`\[([^\]]*)\.([0-9]{3})\s([0-9]{4})\].*` + rotationMarker)
+    threshold := time.Date(2026, time.July, 28, 0, 0, 0, 0, time.UTC)
+
+    file, err := os.CreateTemp(t.TempDir(), "searchd.log")
+    if err != nil {
+        t.Fatalf("CreateTemp: %v", err)
+    }
+    defer file.Close()
+
+    normalLine := "[Tue Jul 28 01:00:00.000 2026] indexing index 'products'\n"
+    if _, err := file.WriteString(normalLine); err != nil {
+        t.Fatalf("WriteString: %v", err)
+    }
+
+    matched, offset, err := scanNewLines(file, 0, re, threshold)
+    if err != nil {
+        t.Fatalf("scanNewLines (first write): %v", err)
+    }
+    if matched {
+        t.Fatal("first write matched the rotation marker, but it doesn't contain one")
+    }
+    if offset != int64(len(normalLine)) {
+        t.Fatalf("offset = %d, want %d (end of the first write)", offset, len(normalLine))
+    }
+
+    markerLine := "[Tue Jul 28 01:00:01.000 2026] " + rotationMarker + "\n"
+    if _, err := file.WriteString(markerLine); err != nil {
+        t.Fatalf("WriteString: %v", err)
+    }
+
+    matched, offset, err = scanNewLines(file, offset, re, threshold)
+    if err != nil {
+        t.Fatalf("scanNewLines (second write): %v", err)
+    }
+    if !matched {
+        t.Fatal("second write did not match the rotation marker - leading byte likely dropped by an off-by-one in consumed")
+    }
+    if want := int64(len(normalLine) + len(markerLine)); offset != want {
+        t.Fatalf("offset = %d, want %d", offset, want)
+    }
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+    "fmt"
+    "time"
+
+    "github.com/skurik/sphinx-reindexer/scheduler"
+)
+
+func buildScheduleEntries(entries []ScheduleEntry) ([]scheduler.Entry, error) {
+    built := make([]scheduler.Entry, 0, len(entries))
+    for _, e := range entries {
+        entry := scheduler.Entry{Index: e.Index, Cron: e.Cron}
+        if e.Every != "" {
+            every, err := time.ParseDuration(e.Every)
+            if err != nil {
+                return nil, fmt.Errorf("schedule entry for %q has an invalid Every value %q: %w", e.Index, e.Every, err)
+            }
+            entry.Every = every
+        }
+        built = append(built, entry)
+    }
+    return built, nil
+}
@@ -0,0 +1,89 @@
+package scheduler
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// searchWindow bounds how far nextCronFire will look for a match before
+// giving up; a year comfortably covers every realistic cron expression.
+const searchWindow = 366 * 24 * time.Hour
+
+// nextCronFire returns the next time after from that matches expr, a
+// standard five-field "minute hour day-of-month month day-of-week" cron
+// expression. Fields support "*" or a comma-separated list of values; step
+// and range syntax are not implemented.
+func nextCronFire(expr string, from time.Time) (time.Time, error) {
+    fields := strings.Fields(expr)
+    if len(fields) != 5 {
+        return time.Time{}, fmt.Errorf("invalid cron expression %q: expected 5 fields, got %d", expr, len(fields))
+    }
+
+    minutes, err := parseCronField(fields[0], 0, 59)
+    if err != nil {
+        return time.Time{}, err
+    }
+    hours, err := parseCronField(fields[1], 0, 23)
+    if err != nil {
+        return time.Time{}, err
+    }
+    daysOfMonth, err := parseCronField(fields[2], 1, 31)
+    if err != nil {
+        return time.Time{}, err
+    }
+    months, err := parseCronField(fields[3], 1, 12)
+    if err != nil {
+        return time.Time{}, err
+    }
+    daysOfWeek, err := parseCronField(fields[4], 0, 6)
+    if err != nil {
+        return time.Time{}, err
+    }
+
+    // Standard cron semantics: if both day-of-month and day-of-week are
+    // restricted (not "*"), a day matches when EITHER one does, not both.
+    domRestricted := fields[2] != "*"
+    dowRestricted := fields[4] != "*"
+
+    t := from.Truncate(time.Minute).Add(time.Minute)
+    for deadline := from.Add(searchWindow); t.Before(deadline); t = t.Add(time.Minute) {
+        if !(months[int(t.Month())] && hours[t.Hour()] && minutes[t.Minute()]) {
+            continue
+        }
+
+        dayMatches := daysOfMonth[t.Day()] && daysOfWeek[int(t.Weekday())]
+        if domRestricted && dowRestricted {
+            dayMatches = daysOfMonth[t.Day()] || daysOfWeek[int(t.Weekday())]
+        }
+        if dayMatches {
+            return t, nil
+        }
+    }
+
+    return time.Time{}, fmt.Errorf("no time within %s matches cron expression %q", searchWindow, expr)
+}
+
+func parseCronField(field string, min, max int) (map[int]bool, error) {
+    values := make(map[int]bool)
+    if field == "*" {
+        for v := min; v <= max; v++ {
+            values[v] = true
+        }
+        return values, nil
+    }
+
+    for _, part := range strings.Split(field, ",") {
+        v, err := strconv.Atoi(part)
+        if err != nil {
+            return nil, fmt.Errorf("invalid cron field value %q", part)
+        }
+        if v < min || v > max {
+            return nil, fmt.Errorf("cron field value %d out of range [%d,%d]", v, min, max)
+        }
+        values[v] = true
+    }
+
+    return values, nil
+}
@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+    "testing"
+    "time"
+)
+
+func mustParse(t *testing.T, value string) time.Time {
+    t.Helper()
+    parsed, err := time.Parse("2006-01-02 15:04", value)
+    if err != nil {
+        t.Fatalf("bad fixture time %q: %v", value, err)
+    }
+    return parsed
+}
+
+func TestNextCronFireEveryField(t *testing.T) {
+    from := mustParse(t, "2026-07-28 10:00")
+    next, err := nextCronFire("0 3 * * *", from)
+    if err != nil {
+        t.Fatalf("nextCronFire returned error: %v", err)
+    }
+    want := mustParse(t, "2026-07-29 03:00")
+    if !next.Equal(want) {
+        t.Errorf("next = %v, want %v", next, want)
+    }
+}
+
+func TestNextCronFireDayOfMonthOrDayOfWeek(t *testing.T) {
+    // "0 0 1 * 1" means midnight on the 1st of the month OR any Monday,
+    // per standard cron semantics - not only when both hold. 2026-08-01
+    // (a Saturday) arrives before the next Monday (2026-08-03), so the
+    // day-of-month branch of the OR should fire first.
+    from := mustParse(t, "2026-07-28 00:00") // a Tuesday, not the 1st
+    next, err := nextCronFire("0 0 1 * 1", from)
+    if err != nil {
+        t.Fatalf("nextCronFire returned error: %v", err)
+    }
+    want := mustParse(t, "2026-08-01 00:00")
+    if !next.Equal(want) {
+        t.Errorf("next = %v, want %v (OR semantics between day-of-month and day-of-week)", next, want)
+    }
+}
+
+func TestNextCronFireInvalidExpression(t *testing.T) {
+    if _, err := nextCronFire("bad expr", time.Now()); err == nil {
+        t.Fatal("expected an error for a malformed cron expression")
+    }
+}
+
+func TestParseCronFieldWildcard(t *testing.T) {
+    values, err := parseCronField("*", 0, 3)
+    if err != nil {
+        t.Fatalf("parseCronField returned error: %v", err)
+    }
+    for v := 0; v <= 3; v++ {
+        if !values[v] {
+            t.Errorf("expected wildcard field to include %d", v)
+        }
+    }
+}
+
+func TestParseCronFieldOutOfRange(t *testing.T) {
+    if _, err := parseCronField("99", 0, 59); err == nil {
+        t.Fatal("expected an error for an out-of-range cron field value")
+    }
+}
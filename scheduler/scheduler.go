@@ -0,0 +1,256 @@
+// Package scheduler runs reindex jobs on a schedule and serializes them
+// with client-triggered runs of the same index, without any external cron
+// wrapper.
+package scheduler
+
+import (
+    "fmt"
+    "sync"
+    "time"
+)
+
+// ReindexFunc performs the actual reindex for index. It is provided by the
+// caller, typically (*reindexer.Service).Reindex.
+type ReindexFunc func(index string) error
+
+// Entry is a single scheduled reindex job, configured with either a fixed
+// interval (Every) or a five-field cron expression (Cron).
+type Entry struct {
+    Index string
+    Every time.Duration
+    Cron  string
+}
+
+func (e Entry) nextFire(from time.Time) (time.Time, error) {
+    if e.Every > 0 {
+        return from.Add(e.Every), nil
+    }
+    if e.Cron != "" {
+        return nextCronFire(e.Cron, from)
+    }
+    return time.Time{}, fmt.Errorf("schedule entry for %q has neither Every nor Cron set", e.Index)
+}
+
+// IndexStatus reports the latest scheduling state for one index.
+type IndexStatus struct {
+    NextFireAt    time.Time
+    LastSuccessAt time.Time
+    LastFailureAt time.Time
+    LastError     string
+}
+
+// Status is a snapshot of the whole scheduler, returned to the /status
+// endpoint.
+type Status struct {
+    QueueDepth int
+    Indexes    map[string]IndexStatus
+}
+
+// Scheduler runs Entry jobs on their schedule and accepts ad-hoc,
+// client-triggered runs through Trigger. Both share the same per-index
+// serialization and a global concurrency cap, so a scheduled and a
+// client-triggered reindex of the same index never overlap, while distinct
+// indexes may run in parallel up to maxConcurrent.
+type Scheduler struct {
+    reindex ReindexFunc
+    sem     chan struct{}
+
+    mu       sync.Mutex
+    indexes  map[string]*indexState
+    queueLen int
+}
+
+func New(reindex ReindexFunc, maxConcurrent int) *Scheduler {
+    if maxConcurrent <= 0 {
+        maxConcurrent = 1
+    }
+    return &Scheduler{
+        reindex: reindex,
+        sem:     make(chan struct{}, maxConcurrent),
+        indexes: make(map[string]*indexState),
+    }
+}
+
+// Start launches one goroutine per entry that fires the job on its own
+// schedule until stop is closed.
+func (s *Scheduler) Start(entries []Entry, stop <-chan struct{}) {
+    for _, entry := range entries {
+        go s.runEntry(entry, stop)
+    }
+}
+
+func (s *Scheduler) runEntry(entry Entry, stop <-chan struct{}) {
+    for {
+        next, err := entry.nextFire(time.Now())
+        if err != nil {
+            fmt.Println("Error scheduling reindex of", entry.Index, ":", err.Error())
+            return
+        }
+        s.indexState(entry.Index).setNextFire(next)
+
+        select {
+        case <-time.After(time.Until(next)):
+            if err := s.run(entry.Index); err != nil {
+                fmt.Println("Scheduled reindex of", entry.Index, "failed:", err.Error())
+            }
+        case <-stop:
+            return
+        }
+    }
+}
+
+// Trigger runs a client-requested reindex of index, queueing behind any
+// scheduled or in-flight run of the same index.
+func (s *Scheduler) Trigger(index string) error {
+    return s.run(index)
+}
+
+// run executes a reindex of index, coalescing concurrent requests that
+// arrive while a run for the same index is already in flight into a single
+// follow-up run: callers that arrive mid-run all wait on that one follow-up
+// rather than each queueing their own, so a burst of N requests for an
+// index produces at most two runs instead of N.
+func (s *Scheduler) run(index string) error {
+    state := s.indexState(index)
+    return state.runOrJoin(func() error { return s.execute(index, state) })
+}
+
+func (s *Scheduler) execute(index string, state *indexState) error {
+    s.mu.Lock()
+    s.queueLen++
+    s.mu.Unlock()
+
+    s.sem <- struct{}{}
+
+    s.mu.Lock()
+    s.queueLen--
+    s.mu.Unlock()
+
+    err := s.reindex(index)
+
+    <-s.sem
+
+    state.recordResult(err)
+    return err
+}
+
+// Status returns the current queue depth and per-index scheduling state.
+func (s *Scheduler) Status() Status {
+    s.mu.Lock()
+    indexes := make(map[string]*indexState, len(s.indexes))
+    for k, v := range s.indexes {
+        indexes[k] = v
+    }
+    queueLen := s.queueLen
+    s.mu.Unlock()
+
+    st := Status{QueueDepth: queueLen, Indexes: make(map[string]IndexStatus, len(indexes))}
+    for index, state := range indexes {
+        st.Indexes[index] = state.status()
+    }
+    return st
+}
+
+func (s *Scheduler) indexState(index string) *indexState {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    st, ok := s.indexes[index]
+    if !ok {
+        st = &indexState{}
+        s.indexes[index] = st
+    }
+    return st
+}
+
+// indexState serializes and coalesces runs of a single index and tracks its
+// status. statusMu is distinct from the run bookkeeping below so
+// recordResult can be called while a run is still in flight.
+type indexState struct {
+    mu      sync.Mutex
+    running *call // non-nil while a run for this index is executing
+    queued  *call // non-nil if a follow-up run has already been requested
+
+    statusMu sync.Mutex
+    st       IndexStatus
+}
+
+// call is the shared result of one run, fanned out to every caller that
+// coalesced into it.
+type call struct {
+    done chan struct{}
+    err  error
+}
+
+// runOrJoin ensures fn runs at least once more for this index. If a run is
+// already executing, the caller joins the single pending follow-up run
+// (starting one if none is queued yet) instead of starting its own.
+func (s *indexState) runOrJoin(fn func() error) error {
+    s.mu.Lock()
+    if s.running != nil {
+        if s.queued == nil {
+            s.queued = &call{done: make(chan struct{})}
+        }
+        c := s.queued
+        s.mu.Unlock()
+        <-c.done
+        return c.err
+    }
+
+    c := &call{done: make(chan struct{})}
+    s.running = c
+    s.mu.Unlock()
+
+    go s.runChain(c, fn)
+
+    <-c.done
+    return c.err
+}
+
+// runChain runs fn for c, then keeps running any follow-up that was queued
+// while it executed, each time publishing the result to waiters on that
+// follow-up's done channel. It runs detached from the caller that triggered
+// the first run, so that caller returns as soon as its own run completes.
+func (s *indexState) runChain(c *call, fn func() error) {
+    for {
+        c.err = fn()
+        close(c.done)
+
+        s.mu.Lock()
+        next := s.queued
+        s.queued = nil
+        if next == nil {
+            s.running = nil
+            s.mu.Unlock()
+            return
+        }
+        s.running = next
+        s.mu.Unlock()
+
+        c = next
+    }
+}
+
+func (s *indexState) setNextFire(t time.Time) {
+    s.statusMu.Lock()
+    defer s.statusMu.Unlock()
+    s.st.NextFireAt = t
+}
+
+func (s *indexState) recordResult(err error) {
+    s.statusMu.Lock()
+    defer s.statusMu.Unlock()
+    now := time.Now()
+    if err != nil {
+        s.st.LastFailureAt = now
+        s.st.LastError = err.Error()
+    } else {
+        s.st.LastSuccessAt = now
+        s.st.LastError = ""
+    }
+}
+
+func (s *indexState) status() IndexStatus {
+    s.statusMu.Lock()
+    defer s.statusMu.Unlock()
+    return s.st
+}
@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+    "sync"
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+// TestSchedulerSerializesPerIndex asserts that two concurrent triggers for
+// the same index never execute at the same time.
+func TestSchedulerSerializesPerIndex(t *testing.T) {
+    var inFlight int32
+    var overlapped int32
+    reindex := func(index string) error {
+        if atomic.AddInt32(&inFlight, 1) > 1 {
+            atomic.StoreInt32(&overlapped, 1)
+        }
+        defer atomic.AddInt32(&inFlight, -1)
+        time.Sleep(20 * time.Millisecond)
+        return nil
+    }
+
+    s := New(ReindexFunc(reindex), 4)
+
+    var wg sync.WaitGroup
+    for i := 0; i < 5; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            _ = s.Trigger("products")
+        }()
+    }
+    wg.Wait()
+
+    if overlapped != 0 {
+        t.Error("two runs for the same index executed concurrently")
+    }
+}
+
+// TestSchedulerCoalescesConcurrentTriggers asserts that a burst of N
+// concurrent triggers for one index produces far fewer than N actual runs.
+func TestSchedulerCoalescesConcurrentTriggers(t *testing.T) {
+    var runs int32
+    release := make(chan struct{})
+    reindex := func(index string) error {
+        n := atomic.AddInt32(&runs, 1)
+        if n == 1 {
+            <-release // hold the first run open so the rest pile up behind it
+        }
+        return nil
+    }
+
+    s := New(ReindexFunc(reindex), 4)
+
+    const callers = 10
+    var wg sync.WaitGroup
+    wg.Add(callers)
+    for i := 0; i < callers; i++ {
+        go func() {
+            defer wg.Done()
+            _ = s.Trigger("products")
+        }()
+    }
+
+    time.Sleep(20 * time.Millisecond) // let every caller queue behind the first run
+    close(release)
+    wg.Wait()
+
+    if got := atomic.LoadInt32(&runs); got != 2 {
+        t.Errorf("got %d runs for %d concurrent callers, want 2 (one in flight + one coalesced follow-up)", got, callers)
+    }
+}
+
+// TestSchedulerAllowsParallelAcrossIndexes asserts that distinct indexes can
+// run at the same time, up to the configured concurrency cap.
+func TestSchedulerAllowsParallelAcrossIndexes(t *testing.T) {
+    var wg sync.WaitGroup
+    var simultaneous int32
+    var sawBoth int32
+    gate := make(chan struct{})
+    reindexFn := func(index string) error {
+        if atomic.AddInt32(&simultaneous, 1) == 2 {
+            atomic.StoreInt32(&sawBoth, 1)
+            close(gate)
+        }
+        select {
+        case <-gate:
+        case <-time.After(time.Second):
+        }
+        atomic.AddInt32(&simultaneous, -1)
+        return nil
+    }
+
+    s := New(ReindexFunc(reindexFn), 2)
+
+    wg.Add(2)
+    go func() { defer wg.Done(); _ = s.Trigger("products") }()
+    go func() { defer wg.Done(); _ = s.Trigger("comments") }()
+    wg.Wait()
+
+    if sawBoth == 0 {
+        t.Error("expected reindexes of two distinct indexes to run in parallel")
+    }
+}